@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+)
+
+// Assertion is a single named comparison to run against a test's actual
+// FHIRPath evaluation result, in the spirit of Venom's step assertions.
+// Recognized Name values: ShouldEqual, ShouldContain, ShouldHaveLength,
+// ShouldBeEmpty, ShouldMatchRegex, ShouldBeType, ShouldBeGreaterThan.
+type Assertion struct {
+	Name     string      `json:"name"`
+	Expected interface{} `json:"expected,omitempty"`
+}
+
+// AssertionApplied records the outcome of running one Assertion against a
+// test's actual result.
+type AssertionApplied struct {
+	Assertion string `json:"assertion"`
+	Error     string `json:"error,omitempty"`
+	IsOK      bool   `json:"isOk"`
+}
+
+// applyAssertions runs each of a TestCase's declared assertions against the
+// actual evaluation result, returning one AssertionApplied record per
+// assertion in declaration order.
+func applyAssertions(actual []interface{}, assertions []Assertion) []AssertionApplied {
+	applied := make([]AssertionApplied, 0, len(assertions))
+	for _, assertion := range assertions {
+		err := applyAssertion(actual, assertion)
+		applied = append(applied, AssertionApplied{
+			Assertion: assertion.Name,
+			Error:     errString(err),
+			IsOK:      err == nil,
+		})
+	}
+	return applied
+}
+
+// applyAssertion runs a single named comparator against the actual result.
+func applyAssertion(actual []interface{}, assertion Assertion) error {
+	switch assertion.Name {
+	case "ShouldEqual":
+		if !reflect.DeepEqual(actual, assertion.Expected) {
+			return fmt.Errorf("expected %v, got %v", assertion.Expected, actual)
+		}
+
+	case "ShouldContain":
+		for _, item := range actual {
+			if reflect.DeepEqual(item, assertion.Expected) {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected result to contain %v, got %v", assertion.Expected, actual)
+
+	case "ShouldHaveLength":
+		length, err := toInt(assertion.Expected)
+		if err != nil {
+			return fmt.Errorf("ShouldHaveLength: %v", err)
+		}
+		if len(actual) != length {
+			return fmt.Errorf("expected length %d, got %d", length, len(actual))
+		}
+
+	case "ShouldBeEmpty":
+		if len(actual) != 0 {
+			return fmt.Errorf("expected empty result, got %v", actual)
+		}
+
+	case "ShouldMatchRegex":
+		pattern, ok := assertion.Expected.(string)
+		if !ok {
+			return fmt.Errorf("ShouldMatchRegex: expected a string pattern, got %T", assertion.Expected)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("ShouldMatchRegex: invalid pattern %q: %v", pattern, err)
+		}
+		for _, item := range actual {
+			if re.MatchString(fmt.Sprintf("%v", item)) {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a result matching %q, got %v", pattern, actual)
+
+	case "ShouldBeType":
+		typeName, ok := assertion.Expected.(string)
+		if !ok {
+			return fmt.Errorf("ShouldBeType: expected a string type name, got %T", assertion.Expected)
+		}
+		for _, item := range actual {
+			if fmt.Sprintf("%T", item) == typeName {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a result of type %s, got %v", typeName, actual)
+
+	case "ShouldBeGreaterThan":
+		threshold, err := toFloat(assertion.Expected)
+		if err != nil {
+			return fmt.Errorf("ShouldBeGreaterThan: %v", err)
+		}
+		for _, item := range actual {
+			if value, err := toFloat(item); err == nil && value > threshold {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a result greater than %v, got %v", threshold, actual)
+
+	default:
+		return fmt.Errorf("unknown assertion %q", assertion.Name)
+	}
+
+	return nil
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func toInt(v interface{}) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case float64:
+		return int(n), nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", v)
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", v)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyAssertion(t *testing.T) {
+	tests := []struct {
+		name      string
+		actual    []interface{}
+		assertion Assertion
+		wantErr   bool
+	}{
+		{"ShouldEqual match", []interface{}{"a", "b"}, Assertion{Name: "ShouldEqual", Expected: []interface{}{"a", "b"}}, false},
+		{"ShouldEqual mismatch", []interface{}{"a"}, Assertion{Name: "ShouldEqual", Expected: []interface{}{"b"}}, true},
+		{"ShouldContain match", []interface{}{"a", "b"}, Assertion{Name: "ShouldContain", Expected: "b"}, false},
+		{"ShouldContain mismatch", []interface{}{"a", "b"}, Assertion{Name: "ShouldContain", Expected: "c"}, true},
+		{"ShouldHaveLength match", []interface{}{"a", "b"}, Assertion{Name: "ShouldHaveLength", Expected: 2}, false},
+		{"ShouldHaveLength mismatch", []interface{}{"a"}, Assertion{Name: "ShouldHaveLength", Expected: 2}, true},
+		{"ShouldHaveLength non-number", []interface{}{"a"}, Assertion{Name: "ShouldHaveLength", Expected: "two"}, true},
+		{"ShouldBeEmpty match", []interface{}{}, Assertion{Name: "ShouldBeEmpty"}, false},
+		{"ShouldBeEmpty mismatch", []interface{}{"a"}, Assertion{Name: "ShouldBeEmpty"}, true},
+		{"ShouldMatchRegex match", []interface{}{"hello123"}, Assertion{Name: "ShouldMatchRegex", Expected: `\d+`}, false},
+		{"ShouldMatchRegex mismatch", []interface{}{"hello"}, Assertion{Name: "ShouldMatchRegex", Expected: `\d+`}, true},
+		{"ShouldMatchRegex bad pattern", []interface{}{"hello"}, Assertion{Name: "ShouldMatchRegex", Expected: `(`}, true},
+		{"ShouldMatchRegex non-string pattern", []interface{}{"hello"}, Assertion{Name: "ShouldMatchRegex", Expected: 5}, true},
+		{"ShouldBeType match", []interface{}{1}, Assertion{Name: "ShouldBeType", Expected: "int"}, false},
+		{"ShouldBeType mismatch", []interface{}{1}, Assertion{Name: "ShouldBeType", Expected: "string"}, true},
+		{"ShouldBeGreaterThan match", []interface{}{5}, Assertion{Name: "ShouldBeGreaterThan", Expected: 3}, false},
+		{"ShouldBeGreaterThan mismatch", []interface{}{1}, Assertion{Name: "ShouldBeGreaterThan", Expected: 3}, true},
+		{"unknown assertion", []interface{}{"a"}, Assertion{Name: "ShouldFrobnicate"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := applyAssertion(tt.actual, tt.assertion)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("applyAssertion(%v, %+v) error = %v, wantErr %v", tt.actual, tt.assertion, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyAssertions_PreservesOrderAndRecordsOutcome(t *testing.T) {
+	assertions := []Assertion{
+		{Name: "ShouldHaveLength", Expected: 1},
+		{Name: "ShouldBeEmpty"},
+	}
+
+	applied := applyAssertions([]interface{}{"a"}, assertions)
+
+	if len(applied) != 2 {
+		t.Fatalf("expected 2 applied assertions, got %d", len(applied))
+	}
+	if applied[0].Assertion != "ShouldHaveLength" || !applied[0].IsOK || applied[0].Error != "" {
+		t.Errorf("expected ShouldHaveLength to pass with no error, got %+v", applied[0])
+	}
+	if applied[1].Assertion != "ShouldBeEmpty" || applied[1].IsOK || applied[1].Error == "" {
+		t.Errorf("expected ShouldBeEmpty to fail with an error message, got %+v", applied[1])
+	}
+}
+
+func TestErrString(t *testing.T) {
+	if got := errString(nil); got != "" {
+		t.Errorf("errString(nil) = %q, want empty string", got)
+	}
+	if got := errString(errors.New("boom")); got != "boom" {
+		t.Errorf("errString(err) = %q, want %q", got, "boom")
+	}
+}
+
+func TestToInt(t *testing.T) {
+	if n, err := toInt(3); err != nil || n != 3 {
+		t.Errorf("toInt(3) = (%d, %v), want (3, nil)", n, err)
+	}
+	if n, err := toInt(3.0); err != nil || n != 3 {
+		t.Errorf("toInt(3.0) = (%d, %v), want (3, nil)", n, err)
+	}
+	if _, err := toInt("3"); err == nil {
+		t.Error("toInt(\"3\") expected an error, got nil")
+	}
+}
+
+func TestToFloat(t *testing.T) {
+	tests := []struct {
+		in      interface{}
+		want    float64
+		wantErr bool
+	}{
+		{3, 3, false},
+		{int64(3), 3, false},
+		{3.5, 3.5, false},
+		{"3.5", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := toFloat(tt.in)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("toFloat(%#v) = (%v, %v), want (%v, wantErr=%v)", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
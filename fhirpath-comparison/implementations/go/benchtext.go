@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"strings"
+)
+
+// renderBenchmarkText formats benchmark results as the standard `go test
+// -bench` text format, so they can be diffed with benchstat and other
+// tooling that expects that shape.
+func renderBenchmarkText(output BenchmarkOutput) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "goos: %s\n", runtime.GOOS)
+	fmt.Fprintf(&b, "goarch: %s\n", runtime.GOARCH)
+	fmt.Fprintf(&b, "pkg: fhirpath-comparison/implementations/go\n")
+
+	for _, bench := range output.Benchmarks {
+		nsPerOp := bench.AvgTimeMs * 1e6
+		fmt.Fprintf(&b, "Benchmark%s-%d\t%d\t%.0f ns/op\t%.2f ops/sec\n",
+			sanitizeBenchmarkName(bench.Name), runtime.GOMAXPROCS(0), bench.Iterations, nsPerOp, bench.OpsPerSecond)
+	}
+
+	b.WriteString("PASS\n")
+
+	return b.String()
+}
+
+// sanitizeBenchmarkName strips characters the go-benchmark text format
+// doesn't expect in a benchmark name, such as spaces or slashes pulled in
+// from an expression.
+func sanitizeBenchmarkName(name string) string {
+	replacer := strings.NewReplacer(" ", "_", "/", "_")
+	return replacer.Replace(name)
+}
+
+// writeBenchmarkText renders benchmarks as go-benchmark text to path.
+func writeBenchmarkText(path string, output BenchmarkOutput) error {
+	if err := ioutil.WriteFile(path, []byte(renderBenchmarkText(output)), 0644); err != nil {
+		return fmt.Errorf("failed to write benchmark text report: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeBenchmarkName(t *testing.T) {
+	tests := map[string]string{
+		"Patient.name":           "Patient.name",
+		"where(code = 'active')": "where(code_=_'active')",
+		"a/b c":                  "a_b_c",
+	}
+	for in, want := range tests {
+		if got := sanitizeBenchmarkName(in); got != want {
+			t.Errorf("sanitizeBenchmarkName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderBenchmarkText(t *testing.T) {
+	output := BenchmarkOutput{
+		Benchmarks: []BenchmarkResult{
+			{Name: "simple path", Iterations: 100, AvgTimeMs: 0.001, OpsPerSecond: 1000},
+		},
+	}
+
+	text := renderBenchmarkText(output)
+
+	wantSubstrings := []string{"goos:", "goarch:", "pkg: fhirpath-comparison/implementations/go", "Benchmarksimple_path", "PASS"}
+	for _, want := range wantSubstrings {
+		if !strings.Contains(text, want) {
+			t.Errorf("renderBenchmarkText() = %q, want it to contain %q", text, want)
+		}
+	}
+
+	wantLine := fmt.Sprintf("Benchmarksimple_path-%d\t100\t1000 ns/op\t1000.00 ops/sec", runtime.GOMAXPROCS(0))
+	if !strings.Contains(text, wantLine) {
+		t.Errorf("renderBenchmarkText() = %q, want it to contain the benchstat-style line %q", text, wantLine)
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// benchmarkHistoryLimit caps how many past runs recordBenchmarkHistory keeps
+// under results/history/.
+const benchmarkHistoryLimit = 20
+
+// BenchmarkDelta is the comparison of one benchmark between a baseline and
+// a current run.
+type BenchmarkDelta struct {
+	Name              string  `json:"name"`
+	BaselineAvgMs     float64 `json:"baseline_avg_ms"`
+	CurrentAvgMs      float64 `json:"current_avg_ms"`
+	DeltaPercent      float64 `json:"delta_percent"`
+	BaselineMinMs     float64 `json:"baseline_min_ms"`
+	CurrentMinMs      float64 `json:"current_min_ms"`
+	BaselineMaxMs     float64 `json:"baseline_max_ms"`
+	CurrentMaxMs      float64 `json:"current_max_ms"`
+	BaselineOpsPerSec float64 `json:"baseline_ops_per_second"`
+	CurrentOpsPerSec  float64 `json:"current_ops_per_second"`
+	Significant       bool    `json:"significant"`
+	Regressed         bool    `json:"regressed"`
+}
+
+// historyEntry is one benchmark run persisted under results/history/, keyed
+// by the git SHA it was produced from.
+type historyEntry struct {
+	SHA       string          `json:"sha"`
+	Timestamp float64         `json:"timestamp"`
+	Output    BenchmarkOutput `json:"output"`
+}
+
+// runCompareCommand implements the `compare` subcommand: it diffs two
+// go_benchmark_results.json files (or the latest history entry against the
+// current results file) and fails the process if any benchmark regressed
+// beyond -threshold.
+func runCompareCommand(args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 10.0, "maximum allowed regression, in percent, before the process exits non-zero")
+	resultsDir := fs.String("results-dir", "../../results", "directory containing benchmark results and history/")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	history, err := loadBenchmarkHistory(*resultsDir)
+	if err != nil {
+		return fmt.Errorf("failed to load benchmark history: %v", err)
+	}
+
+	var baseline, current BenchmarkOutput
+	switch fs.NArg() {
+	case 2:
+		if baseline, err = readBenchmarkOutput(fs.Arg(0)); err != nil {
+			return fmt.Errorf("failed to read baseline benchmark results: %v", err)
+		}
+		if current, err = readBenchmarkOutput(fs.Arg(1)); err != nil {
+			return fmt.Errorf("failed to read current benchmark results: %v", err)
+		}
+	case 0:
+		if len(history) == 0 {
+			return fmt.Errorf("no benchmark history in %s yet; pass two result file paths explicitly", historyDir(*resultsDir))
+		}
+		baseline = history[len(history)-1].Output
+		if current, err = readBenchmarkOutput(filepath.Join(*resultsDir, "go_benchmark_results.json")); err != nil {
+			return fmt.Errorf("failed to read current benchmark results: %v", err)
+		}
+	default:
+		return fmt.Errorf("compare takes zero or two positional arguments (baseline, current), got %d", fs.NArg())
+	}
+
+	deltas := compareBenchmarks(baseline, current, history, *threshold)
+
+	regressed := false
+	for _, delta := range deltas {
+		icon := "✅"
+		if delta.Regressed {
+			icon = "📉"
+			regressed = true
+		}
+		significance := ""
+		if delta.Significant {
+			significance = " (significant)"
+		}
+		fmt.Printf("  %s %s: %.3fms -> %.3fms (%+.1f%%)%s\n",
+			icon, delta.Name, delta.BaselineAvgMs, delta.CurrentAvgMs, delta.DeltaPercent, significance)
+	}
+
+	if err := recordBenchmarkHistory(*resultsDir, current); err != nil {
+		fmt.Printf("⚠️  Warning: Could not record benchmark history: %v\n", err)
+	}
+
+	if regressed {
+		return fmt.Errorf("one or more benchmarks regressed beyond the %.1f%% threshold", *threshold)
+	}
+
+	fmt.Println("✅ No benchmark regressions detected")
+	return nil
+}
+
+// compareBenchmarks computes a BenchmarkDelta for every benchmark present in
+// both baseline and current, flagging regressions beyond thresholdPercent
+// and significance via a Welch's t-test-style check against historical
+// samples when at least 3 historical runs include that benchmark.
+func compareBenchmarks(baseline, current BenchmarkOutput, history []historyEntry, thresholdPercent float64) []BenchmarkDelta {
+	baselineByName := make(map[string]BenchmarkResult, len(baseline.Benchmarks))
+	for _, b := range baseline.Benchmarks {
+		baselineByName[b.Name] = b
+	}
+
+	var deltas []BenchmarkDelta
+	for _, curr := range current.Benchmarks {
+		base, ok := baselineByName[curr.Name]
+		if !ok {
+			continue
+		}
+
+		deltaPercent := 0.0
+		if base.AvgTimeMs > 0 {
+			deltaPercent = (curr.AvgTimeMs - base.AvgTimeMs) / base.AvgTimeMs * 100
+		}
+
+		deltas = append(deltas, BenchmarkDelta{
+			Name:              curr.Name,
+			BaselineAvgMs:     base.AvgTimeMs,
+			CurrentAvgMs:      curr.AvgTimeMs,
+			DeltaPercent:      deltaPercent,
+			BaselineMinMs:     base.MinTimeMs,
+			CurrentMinMs:      curr.MinTimeMs,
+			BaselineMaxMs:     base.MaxTimeMs,
+			CurrentMaxMs:      curr.MaxTimeMs,
+			BaselineOpsPerSec: base.OpsPerSecond,
+			CurrentOpsPerSec:  curr.OpsPerSecond,
+			Regressed:         deltaPercent > thresholdPercent,
+			Significant:       welchSignificant(curr.AvgTimeMs, historicalSamples(history, curr.Name)),
+		})
+	}
+
+	return deltas
+}
+
+// historicalSamples collects AvgTimeMs from every history entry containing
+// the named benchmark, oldest first.
+func historicalSamples(history []historyEntry, name string) []float64 {
+	var samples []float64
+	for _, entry := range history {
+		for _, b := range entry.Output.Benchmarks {
+			if b.Name == name {
+				samples = append(samples, b.AvgTimeMs)
+				break
+			}
+		}
+	}
+	return samples
+}
+
+// welchSignificant reports whether currentValue differs from the
+// historical sample mean by more than ~2 standard errors, a Welch's
+// t-test-style check of the current run against the population of past
+// runs. At least 3 historical samples are required for the check to run.
+func welchSignificant(currentValue float64, samples []float64) bool {
+	if len(samples) < 3 {
+		return false
+	}
+
+	mean := meanOf(samples)
+	stdDev := stdDevOf(samples, mean)
+	if stdDev == 0 {
+		return currentValue != mean
+	}
+
+	standardError := stdDev / math.Sqrt(float64(len(samples)))
+	tStatistic := (currentValue - mean) / standardError
+
+	return math.Abs(tStatistic) > 2.0
+}
+
+func meanOf(samples []float64) float64 {
+	sum := 0.0
+	for _, s := range samples {
+		sum += s
+	}
+	return sum / float64(len(samples))
+}
+
+func stdDevOf(samples []float64, mean float64) float64 {
+	sumSquares := 0.0
+	for _, s := range samples {
+		sumSquares += (s - mean) * (s - mean)
+	}
+	return math.Sqrt(sumSquares / float64(len(samples)-1))
+}
+
+func readBenchmarkOutput(path string) (BenchmarkOutput, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return BenchmarkOutput{}, err
+	}
+
+	var output BenchmarkOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return BenchmarkOutput{}, err
+	}
+
+	return output, nil
+}
+
+func historyDir(resultsDir string) string {
+	return filepath.Join(resultsDir, "history")
+}
+
+// recordBenchmarkHistory appends a benchmark run to results/history/, keyed
+// by git SHA, trimming to the most recent benchmarkHistoryLimit entries.
+func recordBenchmarkHistory(resultsDir string, output BenchmarkOutput) error {
+	dir := historyDir(resultsDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create benchmark history directory: %v", err)
+	}
+
+	sha := currentGitSHA()
+	entry := historyEntry{SHA: sha, Timestamp: output.Timestamp, Output: output}
+
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal benchmark history entry: %v", err)
+	}
+
+	filename := fmt.Sprintf("%d-%s.json", int64(output.Timestamp), sha)
+	if err := ioutil.WriteFile(filepath.Join(dir, filename), data, 0644); err != nil {
+		return fmt.Errorf("failed to write benchmark history entry: %v", err)
+	}
+
+	return pruneBenchmarkHistory(dir, benchmarkHistoryLimit)
+}
+
+// loadBenchmarkHistory reads all persisted history entries, oldest first.
+// A missing history directory is not an error; it just means there's no
+// history yet.
+func loadBenchmarkHistory(resultsDir string) ([]historyEntry, error) {
+	dir := historyDir(resultsDir)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list benchmark history: %v", err)
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		if !f.IsDir() {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	entries := make([]historyEntry, 0, len(names))
+	for _, name := range names {
+		data, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read benchmark history entry %s: %v", name, err)
+		}
+
+		var entry historyEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse benchmark history entry %s: %v", name, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// pruneBenchmarkHistory keeps only the most recent `limit` entries (by
+// filename, which sorts chronologically since it's timestamp-prefixed).
+func pruneBenchmarkHistory(dir string, limit int) error {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to list benchmark history: %v", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) <= limit {
+		return nil
+	}
+
+	for _, name := range names[:len(names)-limit] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("failed to prune old benchmark history entry %s: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+// currentGitSHA returns the short SHA of the currently checked-out commit,
+// or "unknown" when git isn't available (e.g. a source-only checkout).
+func currentGitSHA() string {
+	out, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMeanOf(t *testing.T) {
+	if got := meanOf([]float64{1, 2, 3}); got != 2 {
+		t.Errorf("meanOf([1,2,3]) = %v, want 2", got)
+	}
+}
+
+func TestStdDevOf(t *testing.T) {
+	samples := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+	mean := meanOf(samples)
+	got := stdDevOf(samples, mean)
+	want := 2.1380899352993947
+	if diff := got - want; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("stdDevOf(...) = %v, want %v", got, want)
+	}
+}
+
+func TestWelchSignificant(t *testing.T) {
+	if welchSignificant(100, []float64{10, 10}) {
+		t.Error("expected fewer than 3 samples to never be significant")
+	}
+	if welchSignificant(10, []float64{10, 10, 10}) {
+		t.Error("expected zero variance and a matching current value to not be significant")
+	}
+	if !welchSignificant(1000, []float64{10, 10, 10}) {
+		t.Error("expected zero variance and a wildly different current value to be significant")
+	}
+	if welchSignificant(10.1, []float64{10, 9, 11, 10, 9, 11, 10}) {
+		t.Error("expected a value within normal variance to not be significant")
+	}
+	if !welchSignificant(1000, []float64{10, 9, 11, 10, 9, 11, 10}) {
+		t.Error("expected a value far outside normal variance to be significant")
+	}
+}
+
+func TestHistoricalSamples(t *testing.T) {
+	history := []historyEntry{
+		{Output: BenchmarkOutput{Benchmarks: []BenchmarkResult{{Name: "a", AvgTimeMs: 1}, {Name: "b", AvgTimeMs: 2}}}},
+		{Output: BenchmarkOutput{Benchmarks: []BenchmarkResult{{Name: "a", AvgTimeMs: 3}}}},
+	}
+
+	got := historicalSamples(history, "a")
+	if len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("historicalSamples(history, \"a\") = %v, want [1 3]", got)
+	}
+
+	if got := historicalSamples(history, "missing"); len(got) != 0 {
+		t.Errorf("historicalSamples(history, \"missing\") = %v, want empty", got)
+	}
+}
+
+func TestCompareBenchmarks(t *testing.T) {
+	baseline := BenchmarkOutput{Benchmarks: []BenchmarkResult{
+		{Name: "a", AvgTimeMs: 10},
+		{Name: "only-in-baseline", AvgTimeMs: 5},
+	}}
+	current := BenchmarkOutput{Benchmarks: []BenchmarkResult{
+		{Name: "a", AvgTimeMs: 12},
+		{Name: "only-in-current", AvgTimeMs: 1},
+	}}
+
+	deltas := compareBenchmarks(baseline, current, nil, 10.0)
+
+	if len(deltas) != 1 {
+		t.Fatalf("expected 1 delta (only benchmarks present in both), got %d: %+v", len(deltas), deltas)
+	}
+
+	delta := deltas[0]
+	if delta.Name != "a" {
+		t.Fatalf("expected delta for benchmark \"a\", got %q", delta.Name)
+	}
+	if !delta.Regressed {
+		t.Errorf("expected a 20%% slowdown past a 10%% threshold to be flagged as regressed")
+	}
+	if diff := delta.DeltaPercent - 20.0; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("DeltaPercent = %v, want 20", delta.DeltaPercent)
+	}
+}
+
+func TestCompareBenchmarks_NoRegressionBelowThreshold(t *testing.T) {
+	baseline := BenchmarkOutput{Benchmarks: []BenchmarkResult{{Name: "a", AvgTimeMs: 10}}}
+	current := BenchmarkOutput{Benchmarks: []BenchmarkResult{{Name: "a", AvgTimeMs: 10.5}}}
+
+	deltas := compareBenchmarks(baseline, current, nil, 10.0)
+
+	if len(deltas) != 1 || deltas[0].Regressed {
+		t.Errorf("expected a 5%% slowdown under a 10%% threshold to not be flagged as regressed, got %+v", deltas)
+	}
+}
+
+func TestRecordAndLoadBenchmarkHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	output := BenchmarkOutput{Timestamp: 1000, Benchmarks: []BenchmarkResult{{Name: "a", AvgTimeMs: 1}}}
+	if err := recordBenchmarkHistory(dir, output); err != nil {
+		t.Fatalf("recordBenchmarkHistory: %v", err)
+	}
+
+	entries, err := loadBenchmarkHistory(dir)
+	if err != nil {
+		t.Fatalf("loadBenchmarkHistory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 history entry after recording one, got %d", len(entries))
+	}
+	if entries[0].Output.Benchmarks[0].Name != "a" {
+		t.Errorf("expected recorded entry to round-trip the benchmark name, got %+v", entries[0])
+	}
+}
+
+func TestLoadBenchmarkHistory_MissingDirIsNotAnError(t *testing.T) {
+	dir := t.TempDir()
+
+	entries, err := loadBenchmarkHistory(filepath.Join(dir, "does-not-exist"))
+	if err != nil {
+		t.Fatalf("expected a missing history directory to not be an error, got %v", err)
+	}
+	if entries != nil {
+		t.Errorf("expected nil entries for a missing history directory, got %v", entries)
+	}
+}
+
+func TestPruneBenchmarkHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	for i := 0; i < 5; i++ {
+		output := BenchmarkOutput{Timestamp: float64(1000 + i)}
+		if err := recordBenchmarkHistory(dir, output); err != nil {
+			t.Fatalf("recordBenchmarkHistory: %v", err)
+		}
+	}
+
+	if err := pruneBenchmarkHistory(historyDir(dir), 2); err != nil {
+		t.Fatalf("pruneBenchmarkHistory: %v", err)
+	}
+
+	entries, err := loadBenchmarkHistory(dir)
+	if err != nil {
+		t.Fatalf("loadBenchmarkHistory: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected pruning down to 2 entries, got %d", len(entries))
+	}
+	if entries[0].Timestamp != 1003 || entries[1].Timestamp != 1004 {
+		t.Errorf("expected the 2 most recent entries (1003, 1004) to survive pruning, got %v, %v",
+			entries[0].Timestamp, entries[1].Timestamp)
+	}
+}
+
+func TestHistoryDir(t *testing.T) {
+	if got, want := historyDir("../../results"), filepath.Join("../../results", "history"); got != want {
+		t.Errorf("historyDir(...) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/shopspring/decimal"
+	"github.com/verily-src/fhirpath-go/fhirpath"
+	"github.com/verily-src/fhirpath-go/fhirpath/fhirjson"
+	"github.com/verily-src/fhirpath-go/fhirpath/system"
+)
+
+// fhirpathModulePath is used to look up the resolved fhirpath-go version at
+// runtime so SystemInfo.FhirpathVersion reflects what's actually linked in,
+// rather than a hand-maintained string that drifts from go.mod.
+const fhirpathModulePath = "github.com/verily-src/fhirpath-go"
+
+// FHIRResource is the parsed resource representation that the fhirpath-go
+// evaluator operates on, as produced by resourceFromJSON and consumed by
+// evaluateExpression.
+type FHIRResource = fhirpath.Resource
+
+// resourceFromJSON unmarshals FHIR JSON (R4) into the resource model that
+// the fhirpath-go evaluator operates on.
+func resourceFromJSON(data []byte) (FHIRResource, error) {
+	resource, err := fhirjson.UnmarshalNew(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal FHIR resource: %v", err)
+	}
+
+	return resource, nil
+}
+
+// expressionCache memoizes compiled FHIRPath expressions so a worker only
+// pays the compilation cost once per distinct expression string it sees.
+// It is not safe for concurrent use; callers give one cache per goroutine.
+type expressionCache struct {
+	compiled map[string]*fhirpath.Expression
+}
+
+func newExpressionCache() *expressionCache {
+	return &expressionCache{compiled: make(map[string]*fhirpath.Expression)}
+}
+
+func (c *expressionCache) compile(expression string) (*fhirpath.Expression, error) {
+	if compiled, ok := c.compiled[expression]; ok {
+		return compiled, nil
+	}
+
+	compiled, err := fhirpath.Compile(expression)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile expression %q: %v", expression, err)
+	}
+
+	c.compiled[expression] = compiled
+	return compiled, nil
+}
+
+// errorClass categorizes a FHIRPath evaluation failure as either a parse
+// error (malformed expression) or a runtime/semantic error (well-formed
+// expression that fails against this resource). The values match the
+// official test suite's <expression invalid="..."> attribute.
+type errorClass string
+
+const (
+	errorClassParse    errorClass = "true"
+	errorClassSemantic errorClass = "semantic"
+)
+
+// classifiedError pairs an evaluation error with the errorClass it belongs
+// to, so negative ("invalid") test cases can check whether the failure
+// matches the class they expect.
+type classifiedError struct {
+	class errorClass
+	err   error
+}
+
+func (e *classifiedError) Error() string { return e.err.Error() }
+func (e *classifiedError) Unwrap() error { return e.err }
+
+// evaluateExpression compiles (via cache) and evaluates a FHIRPath
+// expression against a parsed FHIR resource, returning the result
+// collection as plain Go values suitable for comparison against
+// TestCase.ExpectedOutput. Errors are returned as *classifiedError so
+// callers can tell a compile failure from a runtime/semantic one.
+func evaluateExpression(cache *expressionCache, expression string, resource FHIRResource) ([]interface{}, error) {
+	compiled, err := cache.compile(expression)
+	if err != nil {
+		return nil, &classifiedError{class: errorClassParse, err: err}
+	}
+
+	collection, err := compiled.Evaluate([]fhirpath.Resource{resource})
+	if err != nil {
+		return nil, &classifiedError{
+			class: errorClassSemantic,
+			err:   fmt.Errorf("failed to evaluate expression %q: %v", expression, err),
+		}
+	}
+
+	return collectionToValues(collection), nil
+}
+
+// coerceToPredicateBoolean applies FHIRPath's singleton-evaluation-of-
+// collections rule, used when a collection is evaluated in a boolean
+// context: an empty collection is false, a single boolean is used as-is,
+// and a single non-boolean item is true. Collections with more than one
+// item have no singleton boolean value.
+func coerceToPredicateBoolean(actual []interface{}) (bool, error) {
+	switch len(actual) {
+	case 0:
+		return false, nil
+	case 1:
+		if b, ok := actual[0].(bool); ok {
+			return b, nil
+		}
+		return true, nil
+	default:
+		return false, fmt.Errorf("cannot coerce a %d-item collection to a singleton boolean", len(actual))
+	}
+}
+
+// collectionToValues converts a System-typed fhirpath.Collection into plain
+// Go values (bool, string, float64, ...) so results marshal into the same
+// JSON shape as TestCase.ExpectedOutput.
+func collectionToValues(collection system.Collection) []interface{} {
+	values := make([]interface{}, 0, len(collection))
+	for _, item := range collection {
+		values = append(values, systemValueToInterface(item))
+	}
+	return values
+}
+
+// systemValueToInterface unwraps one of fhirpath-go's System primitive types
+// into the Go value it carries, falling back to its string form for types
+// the comparison harness doesn't need to treat specially (e.g. Quantity).
+func systemValueToInterface(item interface{}) interface{} {
+	switch v := item.(type) {
+	case system.Boolean:
+		return bool(v)
+	case system.String:
+		return string(v)
+	case system.Integer:
+		return int(v)
+	case system.Decimal:
+		f, _ := decimal.Decimal(v).Float64()
+		return f
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// fhirpathLibraryVersion reports the resolved fhirpath-go module version
+// from the binary's build info, for SystemInfo.FhirpathVersion.
+func fhirpathLibraryVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	for _, dep := range info.Deps {
+		if dep.Path == fhirpathModulePath {
+			return dep.Version
+		}
+	}
+
+	return "unknown"
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+)
+
+// JUnitTestSuites is the root <testsuites> element of a JUnit XML report.
+type JUnitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []JUnitTestSuite `xml:"testsuite"`
+}
+
+// JUnitTestSuite is a <testsuite> element, one per TestCase.Group.
+type JUnitTestSuite struct {
+	XMLName   xml.Name        `xml:"testsuite"`
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []JUnitTestCase `xml:"testcase"`
+}
+
+// JUnitTestCase is a <testcase> element for a single TestResult.
+type JUnitTestCase struct {
+	XMLName   xml.Name      `xml:"testcase"`
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitFailure `xml:"failure,omitempty"`
+	Error     *JUnitFailure `xml:"error,omitempty"`
+}
+
+// JUnitFailure backs both <failure> (assertion mismatch) and <error>
+// (evaluation error) child elements.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+// buildJUnitReport groups results by TestCase.Group into one <testsuite>
+// per group, in first-seen order, and renders each result as a <testcase>.
+func buildJUnitReport(results []TestResult) JUnitTestSuites {
+	type suiteAcc struct {
+		suite   JUnitTestSuite
+		timeSec float64
+	}
+
+	var order []string
+	bySuite := make(map[string]*suiteAcc)
+
+	for _, result := range results {
+		acc, ok := bySuite[result.Group]
+		if !ok {
+			acc = &suiteAcc{suite: JUnitTestSuite{Name: result.Group}}
+			bySuite[result.Group] = acc
+			order = append(order, result.Group)
+		}
+
+		timeSec := result.ExecutionTimeMs / 1000.0
+		testCase := JUnitTestCase{
+			ClassName: result.Group,
+			Name:      result.Name,
+			Time:      fmt.Sprintf("%.6f", timeSec),
+		}
+
+		switch result.Status {
+		case "failed":
+			testCase.Failure = &JUnitFailure{
+				Message: fmt.Sprintf("expected %v got %v", result.Expected, result.Actual),
+			}
+			acc.suite.Failures++
+		case "error":
+			testCase.Error = &JUnitFailure{Message: result.Error}
+			acc.suite.Errors++
+		}
+
+		acc.suite.Tests++
+		acc.timeSec += timeSec
+		acc.suite.TestCases = append(acc.suite.TestCases, testCase)
+	}
+
+	var report JUnitTestSuites
+	for _, name := range order {
+		acc := bySuite[name]
+		acc.suite.Time = fmt.Sprintf("%.6f", acc.timeSec)
+		report.Suites = append(report.Suites, acc.suite)
+	}
+
+	return report
+}
+
+// writeJUnitReport renders results as JUnit XML to path.
+func writeJUnitReport(path string, results []TestResult) error {
+	report := buildJUnitReport(results)
+
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JUnit report: %v", err)
+	}
+	data = append([]byte(xml.Header), data...)
+
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %v", err)
+	}
+
+	return nil
+}
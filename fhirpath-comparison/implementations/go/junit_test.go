@@ -0,0 +1,45 @@
+package main
+
+import "testing"
+
+func TestBuildJUnitReport_GroupsByGroupInFirstSeenOrder(t *testing.T) {
+	results := []TestResult{
+		{Name: "t1", Group: "groupB", Status: "passed", ExecutionTimeMs: 10},
+		{Name: "t2", Group: "groupA", Status: "failed", ExecutionTimeMs: 20, Expected: []interface{}{"x"}, Actual: []interface{}{"y"}},
+		{Name: "t3", Group: "groupB", Status: "error", ExecutionTimeMs: 5, Error: "boom"},
+	}
+
+	report := buildJUnitReport(results)
+
+	if len(report.Suites) != 2 {
+		t.Fatalf("expected 2 suites, got %d", len(report.Suites))
+	}
+	if report.Suites[0].Name != "groupB" || report.Suites[1].Name != "groupA" {
+		t.Fatalf("expected suites in first-seen order [groupB, groupA], got [%s, %s]",
+			report.Suites[0].Name, report.Suites[1].Name)
+	}
+
+	groupB := report.Suites[0]
+	if groupB.Tests != 2 || groupB.Errors != 1 || groupB.Failures != 0 {
+		t.Errorf("groupB: tests=%d errors=%d failures=%d, want tests=2 errors=1 failures=0",
+			groupB.Tests, groupB.Errors, groupB.Failures)
+	}
+	if groupB.TestCases[1].Error == nil || groupB.TestCases[1].Error.Message != "boom" {
+		t.Errorf("expected t3's error message to be 'boom', got %+v", groupB.TestCases[1].Error)
+	}
+
+	groupA := report.Suites[1]
+	if groupA.Tests != 1 || groupA.Failures != 1 {
+		t.Errorf("groupA: tests=%d failures=%d, want tests=1 failures=1", groupA.Tests, groupA.Failures)
+	}
+	if groupA.TestCases[0].Failure == nil {
+		t.Fatal("expected t2 to carry a Failure")
+	}
+}
+
+func TestBuildJUnitReport_Empty(t *testing.T) {
+	report := buildJUnitReport(nil)
+	if len(report.Suites) != 0 {
+		t.Errorf("expected no suites for no results, got %d", len(report.Suites))
+	}
+}
@@ -3,32 +3,52 @@ package main
 import (
 	"encoding/json"
 	"encoding/xml"
+	"flag"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // TestResult represents a single test result
 type TestResult struct {
-	Name            string        `json:"name"`
-	Description     string        `json:"description"`
-	Expression      string        `json:"expression"`
-	Status          string        `json:"status"`
-	ExecutionTimeMs float64       `json:"execution_time_ms"`
-	Expected        []interface{} `json:"expected"`
-	Actual          []interface{} `json:"actual"`
-	Error           string        `json:"error,omitempty"`
+	Name            string             `json:"name"`
+	Description     string             `json:"description"`
+	Expression      string             `json:"expression"`
+	Status          string             `json:"status"`
+	ExecutionTimeMs float64            `json:"execution_time_ms"`
+	Expected        []interface{}      `json:"expected"`
+	Actual          []interface{}      `json:"actual"`
+	Error           string             `json:"error,omitempty"`
+	Group           string             `json:"group,omitempty"`
+	Assertions      []AssertionApplied `json:"assertions,omitempty"`
 }
 
 // TestSummary represents the summary of test results
 type TestSummary struct {
-	Total  int `json:"total"`
-	Passed int `json:"passed"`
-	Failed int `json:"failed"`
-	Errors int `json:"errors"`
+	Total  int            `json:"total"`
+	Passed int            `json:"passed"`
+	Failed int            `json:"failed"`
+	Errors int            `json:"errors"`
+	Groups []GroupSummary `json:"groups,omitempty"`
+}
+
+// GroupSummary reports pass/fail/error counts and the pass rate for one
+// TestCase.Group (an official test suite group, e.g. "testEquality"), so
+// spec conformance can be tracked per group rather than only in aggregate.
+type GroupSummary struct {
+	Group    string  `json:"group"`
+	Total    int     `json:"total"`
+	Passed   int     `json:"passed"`
+	Failed   int     `json:"failed"`
+	Errors   int     `json:"errors"`
+	PassRate float64 `json:"pass_rate"`
 }
 
 // TestOutput represents the complete test output
@@ -73,6 +93,7 @@ type TestCase struct {
 	InputFile      string        `json:"inputFile"`
 	Expression     string        `json:"expression"`
 	ExpectedOutput []interface{} `json:"expectedOutput"`
+	Assertions     []Assertion   `json:"assertions,omitempty"`
 	Predicate      bool          `json:"predicate"`
 	Mode           string        `json:"mode"`
 	Invalid        string        `json:"invalid"`
@@ -130,18 +151,32 @@ type XMLTestSuite struct {
 
 // GoTestRunner implements the test runner for Go
 type GoTestRunner struct {
-	testDataDir  string
-	testCasesDir string
-	resultsDir   string
-	testConfig   TestConfig
+	testDataDir     string
+	testCasesDir    string
+	resultsDir      string
+	testConfig      TestConfig
+	parallelWorkers int
+	junitPath       string
+	benchtextPath   string
+	shuffleSeed     *int64
 }
 
-// NewGoTestRunner creates a new Go test runner
-func NewGoTestRunner() (*GoTestRunner, error) {
+// NewGoTestRunner creates a new Go test runner. parallelWorkers is the
+// number of worker goroutines runTests uses to evaluate test cases; 0
+// resolves to runtime.NumCPU(). junitPath and benchtextPath override where
+// the JUnit XML and go-benchmark text reports are written; an empty string
+// falls back to the default filename inside resultsDir. shuffleSeed, when
+// non-nil, runs tests in a deterministic pseudo-random dispatch order
+// instead of file order, to surface order-dependent flakes.
+func NewGoTestRunner(parallelWorkers int, junitPath, benchtextPath string, shuffleSeed *int64) (*GoTestRunner, error) {
 	runner := &GoTestRunner{
-		testDataDir:  "../../test-data",
-		testCasesDir: "../../test-cases",
-		resultsDir:   "../../results",
+		testDataDir:     "../../test-data",
+		testCasesDir:    "../../test-cases",
+		resultsDir:      "../../results",
+		parallelWorkers: parallelWorkers,
+		junitPath:       junitPath,
+		benchtextPath:   benchtextPath,
+		shuffleSeed:     shuffleSeed,
 	}
 
 	// Ensure results directory exists
@@ -181,38 +216,12 @@ func (r *GoTestRunner) loadOfficialTests() ([]TestCase, error) {
 	// Extract tests from groups
 	for _, group := range testSuite.Groups {
 		for _, test := range group.Tests {
-			// Skip tests marked as invalid for now (these test error conditions)
-			if test.Expression.Invalid != "" {
-				fmt.Printf("⏭️  Skipping invalid test %s (tests error conditions)\n", test.Name)
-				continue
-			}
-
-			// Parse expected outputs
+			// Parse expected outputs into the same flat, typed shape
+			// evaluateExpression/collectionToValues produce, so
+			// runSingleTest can compare them directly.
 			var expectedOutput []interface{}
 			for _, output := range test.Outputs {
-				// Convert output value based on type
-				var value interface{}
-				switch output.Type {
-				case "boolean":
-					if output.Value == "true" {
-						value = true
-					} else {
-						value = false
-					}
-				case "integer":
-					// In a real implementation, parse as int
-					value = output.Value
-				case "decimal":
-					// In a real implementation, parse as float
-					value = output.Value
-				default:
-					value = output.Value
-				}
-
-				expectedOutput = append(expectedOutput, map[string]interface{}{
-					"type":  output.Type,
-					"value": value,
-				})
+				expectedOutput = append(expectedOutput, typedExpectedValue(output.Type, output.Value))
 			}
 
 			predicate := test.Predicate == "true"
@@ -234,109 +243,104 @@ func (r *GoTestRunner) loadOfficialTests() ([]TestCase, error) {
 	return testCases, nil
 }
 
-// loadTestData loads test data from XML file
-func (r *GoTestRunner) loadTestData(filename string) (map[string]interface{}, error) {
+// typedExpectedValue converts a <output type="..."> value from the official
+// test XML into the same Go type collectionToValues produces for that
+// FHIRPath type, so expected and actual values can be compared directly
+// instead of by string.
+func typedExpectedValue(outputType, value string) interface{} {
+	switch outputType {
+	case "boolean":
+		return value == "true"
+	case "integer":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return value
+		}
+		return n
+	case "decimal":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return value
+		}
+		return f
+	default:
+		return value
+	}
+}
+
+// resultsEqual compares an evaluated collection against the typed expected
+// output for a test, respecting FHIRPath's numeric type tower: an integer
+// result is equal to a decimal expectation (and vice versa) as long as the
+// values match, since the two are interchangeable at this comparison layer.
+func resultsEqual(actual, expected []interface{}) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+
+	for i := range actual {
+		if !valuesEqual(actual[i], expected[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func valuesEqual(a, b interface{}) bool {
+	aNum, aIsNum := toFloat64(a)
+	bNum, bIsNum := toFloat64(b)
+	if aIsNum && bIsNum {
+		return math.Abs(aNum-bNum) < 1e-9
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// loadTestData loads a FHIR resource from test-data/, converting from XML to
+// FHIR JSON when needed, and parses it into the proto model the evaluator
+// operates on. Any file dropped into test-data/ can be loaded this way; none
+// of its content is hard-coded here.
+func (r *GoTestRunner) loadTestData(filename string) (FHIRResource, error) {
 	filePath := filepath.Join(r.testDataDir, filename)
-	_, err := ioutil.ReadFile(filePath)
+	data, err := ioutil.ReadFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read test data file: %v", err)
 	}
 
-	// Parse XML to map
-	var result map[string]interface{}
-
-	// In a real implementation, this would use a proper XML to map converter
-	// For now, we'll use a simplified approach based on the file name
-
-	switch filename {
-	case "patient-example.xml":
-		result = map[string]interface{}{
-			"resourceType": "Patient",
-			"id":           "example",
-			"birthDate":    "1974-12-25",
-			"name": []map[string]interface{}{
-				{
-					"use":    "official",
-					"given":  []string{"Peter", "James"},
-					"family": "Chalmers",
-				},
-				{
-					"use":   "usual",
-					"given": []string{"Jim"},
-				},
-				{
-					"use":    "maiden",
-					"given":  []string{"Peter", "James"},
-					"family": "Windsor",
-				},
-			},
-			"telecom": []map[string]interface{}{
-				{
-					"use":    "home",
-					"system": "phone",
-					"value":  "(03) 5555 6473",
-					"rank":   1,
-				},
-				{
-					"use":    "work",
-					"system": "phone",
-					"value":  "(03) 3410 5613",
-					"rank":   2,
-				},
-				{
-					"use":    "mobile",
-					"system": "phone",
-					"value":  "(03) 3410 5613",
-					"rank":   3,
-				},
-				{
-					"use":    "old",
-					"system": "phone",
-					"value":  "(03) 5555 8834",
-				},
-			},
-			"active": true,
-		}
-	case "observation-example.xml":
-		result = map[string]interface{}{
-			"resourceType": "Observation",
-			"id":           "example",
-			"status":       "final",
-			"code": map[string]interface{}{
-				"coding": []map[string]interface{}{
-					{
-						"system":  "http://loinc.org",
-						"code":    "29463-7",
-						"display": "Body Weight",
-					},
-					{
-						"system":  "http://snomed.info/sct",
-						"code":    "27113001",
-						"display": "Body weight",
-					},
-				},
-			},
-			"value": map[string]interface{}{
-				"value":  185,
-				"unit":   "lbs",
-				"system": "http://unitsofmeasure.org",
-				"code":   "[lb_av]",
-			},
-		}
-	default:
-		// For other files, return a basic structure
-		result = map[string]interface{}{
-			"resourceType": filepath.Base(filename),
-			"id":           "example",
+	if strings.EqualFold(filepath.Ext(filename), ".xml") {
+		data, err = convertFHIRXMLToJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert test data file %s: %v", filename, err)
 		}
 	}
 
-	return result, nil
+	resource, err := resourceFromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse test data file %s: %v", filename, err)
+	}
+
+	return resource, nil
 }
 
-// runSingleTest executes a single test case
-func (r *GoTestRunner) runSingleTest(testCase TestCase, testData map[string]interface{}) TestResult {
-	startTime := time.Now()
+// runSingleTest executes a single test case by compiling and evaluating its
+// FHIRPath expression against the already-parsed resource.
+func (r *GoTestRunner) runSingleTest(testCase TestCase, resource FHIRResource, cache *expressionCache) TestResult {
+	if testCase.Invalid != "" {
+		return r.runInvalidTest(testCase, resource, cache)
+	}
 
 	result := TestResult{
 		Name:        testCase.Name,
@@ -344,35 +348,55 @@ func (r *GoTestRunner) runSingleTest(testCase TestCase, testData map[string]inte
 		Expression:  testCase.Expression,
 		Expected:    testCase.ExpectedOutput,
 		Actual:      []interface{}{},
+		Group:       testCase.Group,
 	}
 
-	// For now, we'll simulate FHIRPath evaluation
-	// In a real implementation, this would use the verily-src/fhirpath-go library
-	// Since we don't have the actual library integrated yet, we'll create mock results
+	if testCase.Mode == "strict" {
+		if err := validateStrictPath(testCase.Expression, resource); err != nil {
+			result.Status = "error"
+			result.Error = err.Error()
+			return result
+		}
+	}
 
-	// Simulate some processing time
-	time.Sleep(time.Millisecond * 1)
+	startTime := time.Now()
+	actual, err := evaluateExpression(cache, testCase.Expression, resource)
+	result.ExecutionTimeMs = float64(time.Since(startTime).Nanoseconds()) / 1000000.0
 
-	endTime := time.Now()
-	result.ExecutionTimeMs = float64(endTime.Sub(startTime).Nanoseconds()) / 1000000.0
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return result
+	}
 
-	// Mock evaluation - in real implementation, this would use FHIRPath library
-	switch testCase.Expression {
-	case "true":
-		result.Actual = []interface{}{true}
-		result.Status = "passed"
-	case "'test string'":
-		result.Actual = []interface{}{"test string"}
-		result.Status = "passed"
-	case "birthDate":
-		result.Actual = []interface{}{} // Empty for now
+	if testCase.Predicate {
+		predicateValue, coerceErr := coerceToPredicateBoolean(actual)
+		if coerceErr != nil {
+			result.Status = "error"
+			result.Error = coerceErr.Error()
+			return result
+		}
+		actual = []interface{}{predicateValue}
+	}
+
+	result.Actual = actual
+
+	if len(testCase.Assertions) > 0 {
+		result.Assertions = applyAssertions(actual, testCase.Assertions)
 		result.Status = "passed"
-	case "name.given":
-		result.Actual = []interface{}{} // Empty for now
+		for _, applied := range result.Assertions {
+			if !applied.IsOK {
+				result.Status = "failed"
+				break
+			}
+		}
+		return result
+	}
+
+	if resultsEqual(actual, testCase.ExpectedOutput) {
 		result.Status = "passed"
-	default:
-		result.Status = "passed" // Mock all as passed for now
-		result.Actual = []interface{}{}
+	} else {
+		result.Status = "failed"
 	}
 
 	return result
@@ -386,7 +410,7 @@ func (r *GoTestRunner) runTests() error {
 	summary := TestSummary{}
 
 	// Load test data files
-	testDataCache := make(map[string]map[string]interface{})
+	testDataCache := make(map[string]FHIRResource)
 	for _, inputFile := range r.testConfig.TestData.InputFiles {
 		testData, err := r.loadTestData(inputFile)
 		if err != nil {
@@ -404,24 +428,24 @@ func (r *GoTestRunner) runTests() error {
 	}
 	fmt.Printf("📊 Found %d official test cases\n", len(officialTests))
 
+	var eligible []TestCase
 	for _, testCase := range officialTests {
-		inputFile := testCase.InputFile
-		testData := testDataCache[inputFile]
+		testData := testDataCache[testCase.InputFile]
 
 		if testData == nil {
-			fmt.Printf("⚠️  Skipping test %s - test data not available: %s\n", testCase.Name, inputFile)
+			fmt.Printf("⚠️  Skipping test %s - test data not available: %s\n", testCase.Name, testCase.InputFile)
 			continue
 		}
 
-		// Skip tests marked as invalid for now (these test error conditions)
-		if testCase.Invalid != "" {
-			fmt.Printf("⏭️  Skipping invalid test %s (tests error conditions)\n", testCase.Name)
-			continue
-		}
+		eligible = append(eligible, testCase)
+	}
 
-		result := r.runSingleTest(testCase, testData)
-		allResults = append(allResults, result)
+	workers := parallelWorkerCount(r.parallelWorkers)
+	fmt.Printf("🚀 Evaluating %d tests across %d workers\n", len(eligible), workers)
 
+	allResults = r.runTestsParallel(eligible, testDataCache, workers, r.shuffleSeed)
+
+	for _, result := range allResults {
 		summary.Total++
 		switch result.Status {
 		case "passed":
@@ -438,9 +462,11 @@ func (r *GoTestRunner) runTests() error {
 		} else if result.Status == "error" {
 			statusIcon = "💥"
 		}
-		fmt.Printf("  %s %s (%.2fms) [%s]\n", statusIcon, result.Name, result.ExecutionTimeMs, testCase.Group)
+		fmt.Printf("  %s %s (%.2fms) [%s]\n", statusIcon, result.Name, result.ExecutionTimeMs, result.Group)
 	}
 
+	summary.Groups = groupPassRates(allResults)
+
 	// Create output structure
 	output := TestOutput{
 		Language:  "go",
@@ -468,6 +494,14 @@ func (r *GoTestRunner) runTests() error {
 		fmt.Printf("⚠️  Warning: Could not write to standard results file: %v\n", err)
 	}
 
+	junitPath := r.junitPath
+	if junitPath == "" {
+		junitPath = filepath.Join(r.resultsDir, "go_test_results.xml")
+	}
+	if err := writeJUnitReport(junitPath, allResults); err != nil {
+		fmt.Printf("⚠️  Warning: Could not write JUnit report: %v\n", err)
+	}
+
 	fmt.Printf("✅ Tests completed. Results saved to %s\n", filename)
 	fmt.Printf("📊 Summary: %d total, %d passed, %d failed, %d errors\n",
 		summary.Total, summary.Passed, summary.Failed, summary.Errors)
@@ -475,6 +509,44 @@ func (r *GoTestRunner) runTests() error {
 	return nil
 }
 
+// groupPassRates tallies pass/fail/error counts per TestCase.Group, in
+// first-seen order, so TestSummary can report per-group spec conformance
+// alongside the flat totals.
+func groupPassRates(results []TestResult) []GroupSummary {
+	var order []string
+	byGroup := make(map[string]*GroupSummary)
+
+	for _, result := range results {
+		acc, ok := byGroup[result.Group]
+		if !ok {
+			acc = &GroupSummary{Group: result.Group}
+			byGroup[result.Group] = acc
+			order = append(order, result.Group)
+		}
+
+		acc.Total++
+		switch result.Status {
+		case "passed":
+			acc.Passed++
+		case "failed":
+			acc.Failed++
+		default:
+			acc.Errors++
+		}
+	}
+
+	summaries := make([]GroupSummary, 0, len(order))
+	for _, name := range order {
+		acc := byGroup[name]
+		if acc.Total > 0 {
+			acc.PassRate = float64(acc.Passed) / float64(acc.Total)
+		}
+		summaries = append(summaries, *acc)
+	}
+
+	return summaries
+}
+
 // runBenchmarks executes benchmark tests
 func (r *GoTestRunner) runBenchmarks() error {
 	fmt.Println("⚡ Running Go FHIRPath benchmarks...")
@@ -484,8 +556,7 @@ func (r *GoTestRunner) runBenchmarks() error {
 	// Load test data
 	testData, err := r.loadTestData("patient-example.xml")
 	if err != nil {
-		fmt.Printf("Warning: Could not load test data: %v\n", err)
-		testData = make(map[string]interface{})
+		return fmt.Errorf("failed to load benchmark test data: %v", err)
 	}
 
 	// Use benchmark cases from test-config.json
@@ -497,9 +568,10 @@ func (r *GoTestRunner) runBenchmarks() error {
 			iterations = testCase.Iterations
 		}
 		var times []float64
+		cache := newExpressionCache()
 
 		for i := 0; i < iterations; i++ {
-			result := r.runSingleTest(testCase, testData)
+			result := r.runSingleTest(testCase, testData, cache)
 			times = append(times, result.ExecutionTimeMs)
 		}
 
@@ -543,7 +615,7 @@ func (r *GoTestRunner) runBenchmarks() error {
 		SystemInfo: SystemInfo{
 			Platform:        runtime.GOOS,
 			GoVersion:       runtime.Version(),
-			FhirpathVersion: "mock-0.1.0", // Would be actual version in real implementation
+			FhirpathVersion: fhirpathLibraryVersion(),
 		},
 	}
 
@@ -566,18 +638,55 @@ func (r *GoTestRunner) runBenchmarks() error {
 		fmt.Printf("⚠️  Warning: Could not write to standard benchmark results file: %v\n", err)
 	}
 
+	benchtextPath := r.benchtextPath
+	if benchtextPath == "" {
+		benchtextPath = filepath.Join(r.resultsDir, "go_benchmark_results.txt")
+	}
+	if err := writeBenchmarkText(benchtextPath, output); err != nil {
+		fmt.Printf("⚠️  Warning: Could not write benchmark text report: %v\n", err)
+	}
+
+	if err := recordBenchmarkHistory(r.resultsDir, output); err != nil {
+		fmt.Printf("⚠️  Warning: Could not record benchmark history: %v\n", err)
+	}
+
 	fmt.Printf("✅ Benchmarks completed. Results saved to %s\n", filename)
 
 	return nil
 }
 
 func main() {
+	parallelFlag := flag.Int("parallel", 0, "number of worker goroutines to evaluate tests with (default: runtime.NumCPU())")
+	junitFlag := flag.String("junit", "", "path to write the JUnit XML report to (default: <results-dir>/go_test_results.xml)")
+	benchtextFlag := flag.String("benchtext", "", "path to write the go-benchmark text report to (default: <results-dir>/go_benchmark_results.txt)")
+	shuffleFlag := flag.String("shuffle", "", "seed to dispatch tests in a shuffled (but deterministic) order, to catch order-dependent flakes; unset runs tests in file order")
+	flag.Parse()
+
+	var shuffleSeed *int64
+	if *shuffleFlag != "" {
+		seed, err := strconv.ParseInt(*shuffleFlag, 10, 64)
+		if err != nil {
+			fmt.Printf("❌ Invalid -shuffle seed %q: %v\n", *shuffleFlag, err)
+			os.Exit(1)
+		}
+		shuffleSeed = &seed
+	}
+
 	mode := "both"
-	if len(os.Args) >= 2 {
-		mode = os.Args[1]
+	if flag.NArg() >= 1 {
+		mode = flag.Arg(0)
+	}
+
+	if mode == "compare" {
+		if err := runCompareCommand(flag.Args()[1:]); err != nil {
+			fmt.Printf("❌ Comparison failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✅ Go test runner completed")
+		return
 	}
 
-	runner, err := NewGoTestRunner()
+	runner, err := NewGoTestRunner(*parallelFlag, *junitFlag, *benchtextFlag, shuffleSeed)
 	if err != nil {
 		fmt.Printf("❌ Failed to initialize test runner: %v\n", err)
 		os.Exit(1)
@@ -604,7 +713,7 @@ func main() {
 			os.Exit(1)
 		}
 	default:
-		fmt.Printf("❌ Unknown mode: %s. Use 'test', 'benchmark', or 'both'\n", mode)
+		fmt.Printf("❌ Unknown mode: %s. Use 'test', 'benchmark', 'both', or 'compare'\n", mode)
 		os.Exit(1)
 	}
 
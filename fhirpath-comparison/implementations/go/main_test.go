@@ -0,0 +1,91 @@
+package main
+
+import "testing"
+
+func TestTypedExpectedValue(t *testing.T) {
+	tests := []struct {
+		outputType string
+		value      string
+		want       interface{}
+	}{
+		{"boolean", "true", true},
+		{"boolean", "false", false},
+		{"integer", "42", 42},
+		{"integer", "not-a-number", "not-a-number"},
+		{"decimal", "3.14", 3.14},
+		{"decimal", "not-a-number", "not-a-number"},
+		{"string", "hello", "hello"},
+	}
+
+	for _, tt := range tests {
+		if got := typedExpectedValue(tt.outputType, tt.value); got != tt.want {
+			t.Errorf("typedExpectedValue(%q, %q) = %#v, want %#v", tt.outputType, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestResultsEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		actual   []interface{}
+		expected []interface{}
+		want     bool
+	}{
+		{"equal strings", []interface{}{"a", "b"}, []interface{}{"a", "b"}, true},
+		{"different lengths", []interface{}{"a"}, []interface{}{"a", "b"}, false},
+		{"int vs decimal cross type", []interface{}{1}, []interface{}{1.0}, true},
+		{"int64 vs float64", []interface{}{int64(2)}, []interface{}{2.0}, true},
+		{"mismatched numbers", []interface{}{1}, []interface{}{2}, false},
+		{"empty both", []interface{}{}, []interface{}{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultsEqual(tt.actual, tt.expected); got != tt.want {
+				t.Errorf("resultsEqual(%v, %v) = %v, want %v", tt.actual, tt.expected, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValuesEqual(t *testing.T) {
+	if !valuesEqual(1, 1.0) {
+		t.Error("expected int 1 to equal float64 1.0")
+	}
+	if !valuesEqual(int64(3), 3.0) {
+		t.Error("expected int64 3 to equal float64 3.0")
+	}
+	if valuesEqual(1, 2) {
+		t.Error("expected 1 != 2")
+	}
+	if !valuesEqual("a", "a") {
+		t.Error("expected equal strings to compare equal")
+	}
+	if valuesEqual("a", "b") {
+		t.Error("expected different strings to compare unequal")
+	}
+	if valuesEqual(true, "true") {
+		t.Error("expected a bool and string to never compare equal")
+	}
+}
+
+func TestToFloat64(t *testing.T) {
+	tests := []struct {
+		in     interface{}
+		want   float64
+		wantOk bool
+	}{
+		{1, 1, true},
+		{int64(2), 2, true},
+		{3.5, 3.5, true},
+		{"3.5", 0, false},
+		{true, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := toFloat64(tt.in)
+		if ok != tt.wantOk || (ok && got != tt.want) {
+			t.Errorf("toFloat64(%#v) = (%v, %v), want (%v, %v)", tt.in, got, ok, tt.want, tt.wantOk)
+		}
+	}
+}
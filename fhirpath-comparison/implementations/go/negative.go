@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// runInvalidTest evaluates a negative ("invalid") test case from the
+// official suite. It is expected to fail, and passes only if the failure's
+// class matches what TestCase.Invalid asked for ("true" => parse error,
+// "semantic" => runtime/semantic error).
+func (r *GoTestRunner) runInvalidTest(testCase TestCase, resource FHIRResource, cache *expressionCache) TestResult {
+	result := TestResult{
+		Name:        testCase.Name,
+		Description: testCase.Description,
+		Expression:  testCase.Expression,
+		Expected:    testCase.ExpectedOutput,
+		Actual:      []interface{}{},
+		Group:       testCase.Group,
+	}
+
+	startTime := time.Now()
+	var actual []interface{}
+	var err error
+	if testCase.Mode == "strict" {
+		if strictErr := validateStrictPath(testCase.Expression, resource); strictErr != nil {
+			err = &classifiedError{class: errorClassSemantic, err: strictErr}
+		}
+	}
+	if err == nil {
+		actual, err = evaluateExpression(cache, testCase.Expression, resource)
+	}
+	result.ExecutionTimeMs = float64(time.Since(startTime).Nanoseconds()) / 1000000.0
+
+	if err == nil {
+		result.Actual = actual
+		result.Status = "failed"
+		result.Error = "expected evaluation to fail, but it succeeded"
+		return result
+	}
+
+	classified, ok := err.(*classifiedError)
+	if !ok {
+		result.Status = "error"
+		result.Error = err.Error()
+		return result
+	}
+
+	if string(classified.class) == testCase.Invalid {
+		result.Status = "passed"
+		result.Error = classified.Error()
+		return result
+	}
+
+	result.Status = "failed"
+	result.Error = fmt.Sprintf("expected a %s error, got a %s error: %v", testCase.Invalid, classified.class, classified.err)
+	return result
+}
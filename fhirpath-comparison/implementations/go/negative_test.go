@@ -0,0 +1,68 @@
+package main
+
+import "testing"
+
+func negativeTestResource(t *testing.T) FHIRResource {
+	t.Helper()
+	resource, err := resourceFromJSON([]byte(`{"resourceType":"Patient","id":"example","active":true}`))
+	if err != nil {
+		t.Fatalf("resourceFromJSON: %v", err)
+	}
+	return resource
+}
+
+func TestRunInvalidTest_ParseErrorExpected(t *testing.T) {
+	testCase := TestCase{
+		Name:       "bad syntax",
+		Expression: "Patient.(",
+		Invalid:    string(errorClassParse),
+	}
+
+	result := (&GoTestRunner{}).runInvalidTest(testCase, negativeTestResource(t), newExpressionCache())
+
+	if result.Status != "passed" {
+		t.Fatalf("expected a parse error to satisfy invalid=%q, got status %q, error %q", testCase.Invalid, result.Status, result.Error)
+	}
+}
+
+func TestRunInvalidTest_SemanticErrorExpected(t *testing.T) {
+	testCase := TestCase{
+		Name:       "type mismatch",
+		Expression: "'abc' + 1",
+		Invalid:    string(errorClassSemantic),
+	}
+
+	result := (&GoTestRunner{}).runInvalidTest(testCase, negativeTestResource(t), newExpressionCache())
+
+	if result.Status != "passed" {
+		t.Fatalf("expected a semantic error to satisfy invalid=%q, got status %q, error %q", testCase.Invalid, result.Status, result.Error)
+	}
+}
+
+func TestRunInvalidTest_WrongErrorClassFails(t *testing.T) {
+	testCase := TestCase{
+		Name:       "parse error expected, semantic error given",
+		Expression: "'abc' + 1",
+		Invalid:    string(errorClassParse),
+	}
+
+	result := (&GoTestRunner{}).runInvalidTest(testCase, negativeTestResource(t), newExpressionCache())
+
+	if result.Status != "failed" {
+		t.Fatalf("expected a %s error to fail an invalid=%q expectation, got status %q", errorClassSemantic, testCase.Invalid, result.Status)
+	}
+}
+
+func TestRunInvalidTest_UnexpectedSuccessFails(t *testing.T) {
+	testCase := TestCase{
+		Name:       "valid expression",
+		Expression: "Patient.active",
+		Invalid:    string(errorClassSemantic),
+	}
+
+	result := (&GoTestRunner{}).runInvalidTest(testCase, negativeTestResource(t), newExpressionCache())
+
+	if result.Status != "failed" {
+		t.Fatalf("expected a successful evaluation to fail the invalid-test expectation, got status %q", result.Status)
+	}
+}
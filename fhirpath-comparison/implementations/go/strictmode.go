@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// validateStrictPath implements mode="strict" for the subset of expressions
+// that are a plain dotted path of member accesses (e.g. "Patient.name.given"):
+// it walks the resource's proto schema segment by segment and errors on the
+// first one that doesn't name a declared field, instead of letting it
+// silently evaluate to an empty collection.
+//
+// fhirpath-go has no strict-mode hook of its own, and expressions using
+// functions, indexing, or union/other operators aren't static dotted paths,
+// so this intentionally only covers the plain-path case and returns nil
+// (nothing to check) for anything else.
+func validateStrictPath(expression string, resource FHIRResource) error {
+	segments, ok := dottedIdentifierSegments(expression)
+	if !ok || len(segments) == 0 {
+		return nil
+	}
+
+	desc := resource.ProtoReflect().Descriptor()
+	if strings.EqualFold(segments[0], string(desc.Name())) {
+		segments = segments[1:]
+	}
+
+	for _, segment := range segments {
+		field := desc.Fields().ByJSONName(segment)
+		if field == nil {
+			return fmt.Errorf("mode=strict: unknown identifier %q", segment)
+		}
+
+		if field.Kind() != protoreflect.MessageKind && field.Kind() != protoreflect.GroupKind {
+			return nil
+		}
+		desc = field.Message()
+	}
+
+	return nil
+}
+
+// dottedIdentifierSegments splits expression on "." and reports whether
+// every segment is a bare identifier, i.e. the whole expression is nothing
+// but a chain of member accesses with no functions, operators, or literals.
+func dottedIdentifierSegments(expression string) ([]string, bool) {
+	trimmed := strings.TrimSpace(expression)
+	if trimmed == "" {
+		return nil, false
+	}
+
+	segments := strings.Split(trimmed, ".")
+	for _, segment := range segments {
+		if !isIdentifier(segment) {
+			return nil, false
+		}
+	}
+
+	return segments, true
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if r == '_' || unicode.IsLetter(r) {
+			continue
+		}
+		if i > 0 && unicode.IsDigit(r) {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestValidateStrictPath_UnknownIdentifierErrors(t *testing.T) {
+	resource := negativeTestResource(t)
+
+	err := validateStrictPath("Patient.bogusField", resource)
+	if err == nil {
+		t.Fatal("expected an error for an unknown identifier under mode=strict")
+	}
+}
+
+func TestValidateStrictPath_KnownPathIsNil(t *testing.T) {
+	resource := negativeTestResource(t)
+
+	if err := validateStrictPath("Patient.active", resource); err != nil {
+		t.Fatalf("expected a known field path to validate, got %v", err)
+	}
+}
+
+func TestValidateStrictPath_NonDottedPathIsNil(t *testing.T) {
+	resource := negativeTestResource(t)
+
+	if err := validateStrictPath("Patient.name.where(use = 'official')", resource); err != nil {
+		t.Fatalf("expected a non-plain-path expression to skip validation, got %v", err)
+	}
+}
+
+func TestDottedIdentifierSegments(t *testing.T) {
+	tests := []struct {
+		expression string
+		wantOk     bool
+		wantLen    int
+	}{
+		{"Patient.name.given", true, 3},
+		{"Patient.name.where(use = 'official')", false, 0},
+		{"", false, 0},
+	}
+
+	for _, tt := range tests {
+		segments, ok := dottedIdentifierSegments(tt.expression)
+		if ok != tt.wantOk {
+			t.Errorf("dottedIdentifierSegments(%q) ok = %v, want %v", tt.expression, ok, tt.wantOk)
+			continue
+		}
+		if ok && len(segments) != tt.wantLen {
+			t.Errorf("dottedIdentifierSegments(%q) = %v, want %d segments", tt.expression, segments, tt.wantLen)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// testJob pairs a TestCase with its already-loaded resource for dispatch to
+// a worker, and its index in the original slice so results can be
+// reassembled in input order.
+type testJob struct {
+	index    int
+	testCase TestCase
+	resource FHIRResource
+}
+
+// testJobResult carries a completed TestResult back to the collector
+// alongside the index of the job it came from.
+type testJobResult struct {
+	index  int
+	result TestResult
+}
+
+// runTestsParallel dispatches testCases across workerCount goroutines, each
+// holding its own compiled-expression cache, and returns results in the same
+// order as testCases regardless of completion order. When shuffleSeed is
+// non-nil, jobs are handed to workers in a deterministic pseudo-random order
+// derived from the seed instead of testCases' order, so that tests relying
+// (even accidentally) on running before or after another test are caught;
+// the returned results are unaffected and still line up with testCases.
+func (r *GoTestRunner) runTestsParallel(testCases []TestCase, testDataCache map[string]FHIRResource, workerCount int, shuffleSeed *int64) []TestResult {
+	jobs := make(chan testJob)
+	collected := make(chan testJobResult)
+
+	var workers sync.WaitGroup
+	for i := 0; i < workerCount; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			cache := newExpressionCache()
+			for job := range jobs {
+				result := r.runSingleTest(job.testCase, job.resource, cache)
+				collected <- testJobResult{index: job.index, result: result}
+			}
+		}()
+	}
+
+	go func() {
+		for _, i := range dispatchOrder(len(testCases), shuffleSeed) {
+			testCase := testCases[i]
+			jobs <- testJob{index: i, testCase: testCase, resource: testDataCache[testCase.InputFile]}
+		}
+		close(jobs)
+	}()
+
+	go func() {
+		workers.Wait()
+		close(collected)
+	}()
+
+	ordered := make([]TestResult, len(testCases))
+	for item := range collected {
+		ordered[item.index] = item.result
+	}
+
+	return ordered
+}
+
+// dispatchOrder returns the sequence of indices [0, n) that jobs should be
+// handed out in. With a nil seed this is just 0..n-1; with a seed it's that
+// same range shuffled deterministically by the seed, via Fisher-Yates.
+func dispatchOrder(n int, shuffleSeed *int64) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	if shuffleSeed == nil {
+		return order
+	}
+
+	rng := rand.New(rand.NewSource(*shuffleSeed))
+	rng.Shuffle(n, func(a, b int) {
+		order[a], order[b] = order[b], order[a]
+	})
+
+	return order
+}
+
+// parallelWorkerCount resolves the -parallel flag's value into an actual
+// worker count, defaulting to runtime.NumCPU() when unset.
+func parallelWorkerCount(requested int) int {
+	if requested > 0 {
+		return requested
+	}
+	return runtime.NumCPU()
+}
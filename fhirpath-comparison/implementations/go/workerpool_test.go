@@ -0,0 +1,72 @@
+package main
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestRunTestsParallel_PreservesInputOrder(t *testing.T) {
+	resource := negativeTestResource(t)
+	testDataCache := map[string]FHIRResource{"": resource}
+
+	testCases := []TestCase{
+		{Name: "active", Expression: "Patient.active", ExpectedOutput: []interface{}{true}},
+		{Name: "id", Expression: "Patient.id", ExpectedOutput: []interface{}{"example"}},
+		{Name: "bogus", Expression: "Patient.nope", ExpectedOutput: []interface{}{}},
+	}
+
+	runner := &GoTestRunner{}
+	results := runner.runTestsParallel(testCases, testDataCache, 4, nil)
+
+	if len(results) != len(testCases) {
+		t.Fatalf("got %d results, want %d", len(results), len(testCases))
+	}
+	for i, result := range results {
+		if result.Name != testCases[i].Name {
+			t.Errorf("results[%d].Name = %q, want %q (order not preserved)", i, result.Name, testCases[i].Name)
+		}
+	}
+}
+
+func TestDispatchOrder_NilSeedIsIdentity(t *testing.T) {
+	order := dispatchOrder(5, nil)
+	for i, v := range order {
+		if v != i {
+			t.Fatalf("dispatchOrder(5, nil) = %v, want identity order", order)
+		}
+	}
+}
+
+func TestDispatchOrder_SameSeedIsDeterministic(t *testing.T) {
+	seed := int64(42)
+	first := dispatchOrder(20, &seed)
+	second := dispatchOrder(20, &seed)
+
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("dispatchOrder with the same seed produced different orders: %v vs %v", first, second)
+		}
+	}
+}
+
+func TestDispatchOrder_IsAPermutation(t *testing.T) {
+	seed := int64(7)
+	order := dispatchOrder(10, &seed)
+
+	seen := make(map[int]bool, len(order))
+	for _, v := range order {
+		seen[v] = true
+	}
+	if len(seen) != 10 {
+		t.Fatalf("dispatchOrder(10, seed) = %v, want a permutation of 0..9", order)
+	}
+}
+
+func TestParallelWorkerCount(t *testing.T) {
+	if got := parallelWorkerCount(8); got != 8 {
+		t.Errorf("parallelWorkerCount(8) = %d, want 8", got)
+	}
+	if got := parallelWorkerCount(0); got != runtime.NumCPU() {
+		t.Errorf("parallelWorkerCount(0) = %d, want runtime.NumCPU() = %d", got, runtime.NumCPU())
+	}
+}
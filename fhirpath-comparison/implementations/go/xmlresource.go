@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	bcrpb "github.com/google/fhir/go/proto/google/fhir/proto/r4/core/resources/bundle_and_contained_resource_go_proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fhirXMLNode is a generic XML element used to walk FHIR XML resources
+// without hard-coding a schema per resource type, so any file under
+// test-data/ can be loaded, not just the ones a switch statement knows about.
+type fhirXMLNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr    `xml:",any,attr"`
+	Children []fhirXMLNode `xml:",any"`
+}
+
+// convertFHIRXMLToJSON converts a FHIR XML resource into the equivalent FHIR
+// JSON representation, following the FHIR XML convention that primitive
+// elements carry their value in a "value" attribute and complex elements
+// nest further elements.
+func convertFHIRXMLToJSON(data []byte) ([]byte, error) {
+	var root fhirXMLNode
+	if err := xml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse FHIR XML: %v", err)
+	}
+
+	resourceType := root.XMLName.Local
+	resource := nodeToJSON(root, resourceDescriptor(resourceType))
+	resource["resourceType"] = resourceType
+
+	jsonData, err := json.Marshal(resource)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal converted FHIR resource: %v", err)
+	}
+
+	return jsonData, nil
+}
+
+// resourceDescriptor looks up the proto schema for a FHIR R4 resource type
+// by name (e.g. "Patient"), via the ContainedResource oneof that names every
+// resource type the FHIR proto model supports. It returns nil for a type it
+// doesn't recognize, in which case nodeToJSON falls back to inferring
+// cardinality from the XML alone.
+func resourceDescriptor(resourceType string) protoreflect.MessageDescriptor {
+	field := (&bcrpb.ContainedResource{}).ProtoReflect().Descriptor().Fields().ByJSONName(lowerFirst(resourceType))
+	if field == nil || field.Kind() != protoreflect.MessageKind {
+		return nil
+	}
+	return field.Message()
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// nodeToJSON converts one FHIR XML element's attributes and children into
+// the map that backs its JSON object, grouping repeated child elements into
+// arrays as FHIR JSON requires. desc, when known, is the proto schema for
+// this element; it's consulted to decide whether a field is repeating
+// rather than guessing from how many children happen to be present, since a
+// schematically-repeating field (e.g. Patient.name) must stay an array even
+// when only one happens to occur, and XML namespace declarations are
+// dropped since they have no FHIR JSON equivalent.
+func nodeToJSON(node fhirXMLNode, desc protoreflect.MessageDescriptor) map[string]interface{} {
+	result := make(map[string]interface{})
+
+	for _, attr := range node.Attrs {
+		if isNamespaceAttr(attr) || attr.Name.Local == "value" {
+			continue
+		}
+		result[attr.Name.Local] = attr.Value
+	}
+
+	var order []string
+	grouped := make(map[string][]fhirXMLNode)
+	for _, child := range node.Children {
+		name := child.XMLName.Local
+		if _, seen := grouped[name]; !seen {
+			order = append(order, name)
+		}
+		grouped[name] = append(grouped[name], child)
+	}
+
+	for _, name := range order {
+		children := grouped[name]
+		childDesc := fieldMessageDescriptor(desc, name)
+		values := make([]interface{}, 0, len(children))
+		for _, child := range children {
+			values = append(values, nodeValue(child, childDesc))
+		}
+
+		if isRepeatedField(desc, name, len(values)) {
+			result[name] = values
+		} else {
+			result[name] = values[0]
+		}
+	}
+
+	if value, ok := attrValue(node, "value"); ok {
+		if isUnquotedJSONPrimitive(desc) {
+			result["value"] = json.RawMessage(value)
+		} else {
+			result["value"] = value
+		}
+	}
+
+	return result
+}
+
+// isNamespaceAttr reports whether attr is an XML namespace declaration
+// (xmlns="..." or xmlns:prefix="..."), which FHIR JSON has no place for.
+func isNamespaceAttr(attr xml.Attr) bool {
+	return attr.Name.Local == "xmlns" || attr.Name.Space == "xmlns"
+}
+
+// fieldMessageDescriptor returns the proto schema for the named field of
+// desc, or nil if desc is unknown, the field isn't declared, or the field
+// isn't itself a message (and so has no further schema to walk into).
+func fieldMessageDescriptor(desc protoreflect.MessageDescriptor, name string) protoreflect.MessageDescriptor {
+	if desc == nil {
+		return nil
+	}
+	field := desc.Fields().ByJSONName(name)
+	if field == nil || field.Kind() != protoreflect.MessageKind {
+		return nil
+	}
+	return field.Message()
+}
+
+// isRepeatedField reports whether the named field of desc is declared
+// repeating in the FHIR schema. When desc is unknown (or doesn't declare
+// the field, e.g. a choice-type element), it falls back to the previous
+// count>1 heuristic rather than guessing wrong in either direction.
+func isRepeatedField(desc protoreflect.MessageDescriptor, name string, count int) bool {
+	if desc != nil {
+		if field := desc.Fields().ByJSONName(name); field != nil {
+			return field.Cardinality() == protoreflect.Repeated
+		}
+	}
+	return count > 1
+}
+
+// nodeValue returns a leaf element's scalar value when it's a FHIR
+// primitive, or its nested JSON object otherwise.
+func nodeValue(node fhirXMLNode, desc protoreflect.MessageDescriptor) interface{} {
+	if value, ok := attrValue(node, "value"); ok && len(node.Children) == 0 {
+		if isUnquotedJSONPrimitive(desc) {
+			return json.RawMessage(value)
+		}
+		return value
+	}
+	return nodeToJSON(node, desc)
+}
+
+// isUnquotedJSONPrimitive reports whether desc is the proto schema for a
+// FHIR primitive type whose JSON representation is a bare boolean or
+// number rather than a string (Boolean, Integer, Decimal, PositiveInt,
+// UnsignedInt). FHIR JSON requires these unquoted, and fhirjson's strict
+// decoder rejects the quoted-string form the XML `value` attribute always
+// carries. desc is nil when the resource type, or this element's field,
+// isn't recognized, in which case the value is left as a string.
+func isUnquotedJSONPrimitive(desc protoreflect.MessageDescriptor) bool {
+	if desc == nil {
+		return false
+	}
+	switch desc.Name() {
+	case "Boolean", "Integer", "Decimal", "PositiveInt", "UnsignedInt":
+		return true
+	default:
+		return false
+	}
+}
+
+func attrValue(node fhirXMLNode, name string) (string, bool) {
+	for _, attr := range node.Attrs {
+		if attr.Name.Local == name {
+			return attr.Value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+)
+
+func TestConvertFHIRXMLToJSON_SingleRepeatingFieldStaysArray(t *testing.T) {
+	xmlData := []byte(`<Patient xmlns="http://hl7.org/fhir">
+  <id value="example"/>
+  <name>
+    <family value="Chalmers"/>
+    <given value="Peter"/>
+  </name>
+</Patient>`)
+
+	jsonData, err := convertFHIRXMLToJSON(xmlData)
+	if err != nil {
+		t.Fatalf("convertFHIRXMLToJSON: %v", err)
+	}
+
+	var resource map[string]interface{}
+	if err := json.Unmarshal(jsonData, &resource); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	if _, ok := resource["name"].([]interface{}); !ok {
+		t.Fatalf("expected name to stay an array for a single occurrence, got %T: %v", resource["name"], resource["name"])
+	}
+
+	names := resource["name"].([]interface{})
+	nameObj, ok := names[0].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected name[0] to be an object, got %T", names[0])
+	}
+	if _, ok := nameObj["given"].([]interface{}); !ok {
+		t.Fatalf("expected given to stay an array for a single occurrence, got %T: %v", nameObj["given"], nameObj["given"])
+	}
+}
+
+func TestConvertFHIRXMLToJSON_RepeatedFieldStaysArray(t *testing.T) {
+	xmlData := []byte(`<Patient xmlns="http://hl7.org/fhir">
+  <name><family value="Chalmers"/></name>
+  <name><family value="Windsor"/></name>
+</Patient>`)
+
+	jsonData, err := convertFHIRXMLToJSON(xmlData)
+	if err != nil {
+		t.Fatalf("convertFHIRXMLToJSON: %v", err)
+	}
+
+	var resource map[string]interface{}
+	if err := json.Unmarshal(jsonData, &resource); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	names, ok := resource["name"].([]interface{})
+	if !ok || len(names) != 2 {
+		t.Fatalf("expected name to be a 2-element array, got %#v", resource["name"])
+	}
+}
+
+func TestConvertFHIRXMLToJSON_RoundTripsThroughFhirjson(t *testing.T) {
+	xmlData := []byte(`<Patient xmlns="http://hl7.org/fhir">
+  <id value="example"/>
+  <active value="true"/>
+  <telecom>
+    <system value="phone"/>
+    <value value="555-0100"/>
+    <rank value="1"/>
+  </telecom>
+</Patient>`)
+
+	jsonData, err := convertFHIRXMLToJSON(xmlData)
+	if err != nil {
+		t.Fatalf("convertFHIRXMLToJSON: %v", err)
+	}
+
+	resource, err := resourceFromJSON(jsonData)
+	if err != nil {
+		t.Fatalf("resourceFromJSON rejected the converted resource: %v\njson: %s", err, jsonData)
+	}
+	if resource == nil {
+		t.Fatal("resourceFromJSON returned a nil resource")
+	}
+}
+
+func TestConvertFHIRXMLToJSON_DropsNamespaceAttrs(t *testing.T) {
+	xmlData := []byte(`<Patient xmlns="http://hl7.org/fhir" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">
+  <gender value="male"/>
+</Patient>`)
+
+	jsonData, err := convertFHIRXMLToJSON(xmlData)
+	if err != nil {
+		t.Fatalf("convertFHIRXMLToJSON: %v", err)
+	}
+
+	var resource map[string]interface{}
+	if err := json.Unmarshal(jsonData, &resource); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	for key := range resource {
+		if key == "xmlns" || key == "xsi" {
+			t.Fatalf("expected namespace attribute %q to be dropped, got keys %v", key, resource)
+		}
+	}
+	if resource["gender"] != "male" {
+		t.Fatalf("expected gender=male, got %v", resource["gender"])
+	}
+}
+
+func TestIsNamespaceAttr(t *testing.T) {
+	tests := []struct {
+		name string
+		attr xml.Attr
+		want bool
+	}{
+		{"default xmlns", xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: "http://hl7.org/fhir"}, true},
+		{"prefixed xmlns", xml.Attr{Name: xml.Name{Space: "xmlns", Local: "xsi"}, Value: "..."}, true},
+		{"ordinary attr", xml.Attr{Name: xml.Name{Local: "value"}, Value: "male"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNamespaceAttr(tt.attr); got != tt.want {
+				t.Errorf("isNamespaceAttr(%+v) = %v, want %v", tt.attr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceDescriptor(t *testing.T) {
+	if desc := resourceDescriptor("Patient"); desc == nil {
+		t.Fatal("expected a descriptor for Patient")
+	}
+	if desc := resourceDescriptor("NotARealResourceType"); desc != nil {
+		t.Fatalf("expected no descriptor for an unknown resource type, got %v", desc)
+	}
+}
+
+func TestIsRepeatedField_FallsBackWithoutSchema(t *testing.T) {
+	if isRepeatedField(nil, "name", 1) {
+		t.Error("expected count=1 with no schema to be treated as non-repeated")
+	}
+	if !isRepeatedField(nil, "name", 2) {
+		t.Error("expected count=2 with no schema to be treated as repeated")
+	}
+}
+
+func TestLowerFirst(t *testing.T) {
+	tests := map[string]string{
+		"Patient":            "patient",
+		"AllergyIntolerance": "allergyIntolerance",
+		"":                   "",
+	}
+	for in, want := range tests {
+		if got := lowerFirst(in); got != want {
+			t.Errorf("lowerFirst(%q) = %q, want %q", in, got, want)
+		}
+	}
+}